@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// defaultBundledPath is where the bundled SRD spell list ships relative to
+// the binary.
+const defaultBundledPath = "data/spells.json"
+
+// bundledSource serves spells from the JSON file shipped alongside the
+// binary. It's banshie's original, always-available data source.
+type bundledSource struct {
+	path string
+}
+
+// newBundledSource returns a source reading the bundled SRD spell list
+// from path.
+func newBundledSource(path string) *bundledSource {
+	return &bundledSource{path: path}
+}
+
+func (s *bundledSource) Name() string { return "SRD" }
+
+func (s *bundledSource) Load(ctx context.Context) ([]Spell, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("reading bundled spells: %w", err)
+	}
+
+	var spells []Spell
+	if err := json.Unmarshal(data, &spells); err != nil {
+		return nil, fmt.Errorf("parsing bundled spells: %w", err)
+	}
+	return spells, nil
+}
+
+func (s *bundledSource) Stream(ctx context.Context, out chan<- Spell) error {
+	spells, err := s.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, spell := range spells {
+		select {
+		case out <- spell:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// completionDebounce bounds how often the completion source is re-queried
+// when Tab is pressed for a changed prefix, so a slow source (a
+// network-backed SpellSource, say) isn't hit on every press. Cycling through
+// the current candidates never re-queries it, and a press within the window
+// reuses whatever candidates are held.
+const completionDebounce = 150 * time.Millisecond
+
+// tabCompleter drives Tab-completion for the search input: it asks source
+// for candidates sharing the field's current prefix, cycles through them on
+// repeated presses, and hands back the ghost suffix that should be
+// previewed after the typed text. Modeled after aerc's TextInput.TabComplete.
+type tabCompleter struct {
+	source func(prefix string) []string
+
+	prefix     string
+	candidates []string
+	index      int
+	active     bool
+	lastQuery  time.Time
+}
+
+// newTabCompleter builds a completer backed by source, a function that
+// returns candidate spell names for a given prefix.
+func newTabCompleter(source func(prefix string) []string) *tabCompleter {
+	return &tabCompleter{source: source, index: -1}
+}
+
+// Next advances the completer for the given input text, returning the
+// ghost suffix that should be appended after it. Returns "" if there's
+// nothing left to complete.
+func (t *tabCompleter) Next(current string) string {
+	stale := !t.active || !strings.HasPrefix(strings.ToLower(current), strings.ToLower(t.prefix))
+	if stale && time.Since(t.lastQuery) >= completionDebounce {
+		t.prefix = current
+		t.candidates = t.source(current)
+		t.index = -1
+		t.active = true
+		t.lastQuery = time.Now()
+	}
+
+	if len(t.candidates) == 0 {
+		return ""
+	}
+
+	t.index = (t.index + 1) % len(t.candidates)
+	candidate := t.candidates[t.index]
+	if len(candidate) <= len(current) {
+		return ""
+	}
+	return candidate[len(current):]
+}
+
+// Reset clears completer state. Called whenever the field changes outside
+// of cycling: typing, accepting a completion, or cancelling.
+func (t *tabCompleter) Reset() {
+	t.active = false
+	t.candidates = nil
+	t.index = -1
+}
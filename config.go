@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// action identifies a named, rebindable operation the global input handler
+// can dispatch to.
+type action string
+
+const (
+	actionFocusList      action = "focus_list"
+	actionFocusDetails   action = "focus_details"
+	actionScrollUp       action = "scroll_up"
+	actionScrollDown     action = "scroll_down"
+	actionClearInput     action = "clear_input"
+	actionTogglePrepared action = "toggle_prepared"
+	actionOpenPalette    action = "open_palette"
+	actionSwitchFocus    action = "switch_focus"
+	actionSelectSpell    action = "select_spell"
+	actionLongRest       action = "long_rest"
+	actionCycleSource    action = "cycle_source"
+)
+
+// actionExpendSlotPrefix namespaces the nine expend_slot_1..expend_slot_9
+// actions, one per spell slot level, since each needs to carry its level
+// through to dispatch.
+const actionExpendSlotPrefix = "expend_slot_"
+
+// config is the user-facing TOML configuration, following phi's
+// cfg.TomlConfig pattern: plain exported fields tagged for the TOML
+// decoder, loaded once at startup and never mutated afterwards.
+type config struct {
+	Keybindings map[string]string `toml:"keybindings"`
+	// Sources lists the SpellSource names to load from, in order, e.g.
+	// ["srd", "dnd5eapi", "pathfinder2e"]. Defaults to ["srd"].
+	Sources []string `toml:"sources"`
+}
+
+// defaultKeybindings covers the keys of the hard-coded switch banshie
+// shipped before config support existed, plus the palette, spellbook and
+// source keys, so a missing or partial config.toml keeps those keys working
+// for anything it doesn't override.
+var defaultKeybindings = map[string]string{
+	"Up":     string(actionScrollUp),
+	"Ctrl-K": string(actionScrollUp),
+	"Down":   string(actionScrollDown),
+	"Ctrl-J": string(actionScrollDown),
+	"Ctrl-D": string(actionClearInput),
+	"Left":   string(actionFocusList),
+	"Ctrl-H": string(actionFocusList),
+	"Right":  string(actionFocusDetails),
+	"Ctrl-L": string(actionFocusDetails),
+	"Tab":    string(actionSwitchFocus),
+	"Enter":  string(actionSelectSpell),
+	":":      string(actionOpenPalette),
+	"p":      string(actionTogglePrepared),
+	"R":      string(actionLongRest),
+	"s":      string(actionCycleSource),
+	"1":      actionExpendSlotPrefix + "1",
+	"2":      actionExpendSlotPrefix + "2",
+	"3":      actionExpendSlotPrefix + "3",
+	"4":      actionExpendSlotPrefix + "4",
+	"5":      actionExpendSlotPrefix + "5",
+	"6":      actionExpendSlotPrefix + "6",
+	"7":      actionExpendSlotPrefix + "7",
+	"8":      actionExpendSlotPrefix + "8",
+	"9":      actionExpendSlotPrefix + "9",
+}
+
+// loadConfig reads $XDG_CONFIG_HOME/banshie/config.toml, falling back to
+// the built-in keybindings for anything missing from it, or entirely if it
+// doesn't exist or fails to parse.
+func loadConfig() *config {
+	cfg := &config{Keybindings: make(map[string]string, len(defaultKeybindings))}
+	for chord, act := range defaultKeybindings {
+		cfg.Keybindings[chord] = act
+	}
+
+	path := configPath()
+	if _, err := os.Stat(path); err != nil {
+		return cfg
+	}
+
+	var loaded config
+	if _, err := toml.DecodeFile(path, &loaded); err != nil {
+		return cfg
+	}
+
+	for chord, act := range loaded.Keybindings {
+		cfg.Keybindings[chord] = act
+	}
+	cfg.Sources = loaded.Sources
+	return cfg
+}
+
+func configPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".config")
+	}
+	return filepath.Join(dir, "banshie", "config.toml")
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// loadAllData loads spells from every configured source, reporting
+// per-source progress through statusChan as each source streams its
+// spells in, and delivers the combined result on dataChan once every
+// source has finished (or failed).
+func loadAllData(sources []SpellSource, dataChan chan<- []Spell, statusChan chan<- string) {
+	ctx := context.Background()
+	var all []Spell
+
+	for _, src := range sources {
+		spells, err := loadSource(ctx, src, statusChan)
+		if err != nil {
+			statusChan <- fmt.Sprintf("%s: %v", src.Name(), err)
+			continue
+		}
+		all = append(all, spells...)
+	}
+
+	dataChan <- all
+}
+
+// loadSource streams spells from src, tagging each with its source and
+// reporting "loading <name>... n" through statusChan as they arrive.
+func loadSource(ctx context.Context, src SpellSource, statusChan chan<- string) ([]Spell, error) {
+	out := make(chan Spell)
+	errChan := make(chan error, 1)
+
+	go func() {
+		errChan <- src.Stream(ctx, out)
+		close(out)
+	}()
+
+	var spells []Spell
+	for spell := range out {
+		spell.Source = src.Name()
+		spells = append(spells, spell)
+		statusChan <- fmt.Sprintf("loading %s... %d", src.Name(), len(spells))
+	}
+
+	if err := <-errChan; err != nil {
+		return nil, err
+	}
+
+	statusChan <- fmt.Sprintf("loaded %s: %d spells", src.Name(), len(spells))
+	return spells, nil
+}
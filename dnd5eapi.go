@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// dnd5eAPISource loads spells from the public dnd5eapi.co HTTP API,
+// caching the combined result on disk under $XDG_CACHE_HOME/banshie so
+// repeat runs don't re-fetch every spell one at a time.
+type dnd5eAPISource struct {
+	baseURL string
+	client  *http.Client
+}
+
+// newDnd5eAPISource returns a source backed by the public dnd5eapi.co API.
+func newDnd5eAPISource() *dnd5eAPISource {
+	return &dnd5eAPISource{
+		baseURL: "https://www.dnd5eapi.co/api",
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *dnd5eAPISource) Name() string { return "D&D 5e API" }
+
+func (s *dnd5eAPISource) Load(ctx context.Context) ([]Spell, error) {
+	if spells, err := s.loadCache(); err == nil {
+		return spells, nil
+	}
+
+	out := make(chan Spell)
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- s.Stream(ctx, out)
+		close(out)
+	}()
+
+	spells := make([]Spell, 0)
+	for spell := range out {
+		spells = append(spells, spell)
+	}
+	if err := <-errChan; err != nil {
+		return nil, err
+	}
+
+	s.saveCache(spells)
+	return spells, nil
+}
+
+// Stream walks the /spells index and emits each spell as soon as its own
+// detail page has been fetched and parsed, rather than waiting for the
+// whole index to be resolved first.
+func (s *dnd5eAPISource) Stream(ctx context.Context, out chan<- Spell) error {
+	if spells, err := s.loadCache(); err == nil {
+		for _, spell := range spells {
+			select {
+			case out <- spell:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	}
+
+	var index dnd5eSpellIndex
+	if err := s.getJSON(ctx, s.baseURL+"/spells", &index); err != nil {
+		return err
+	}
+
+	spells := make([]Spell, 0, len(index.Results))
+	for _, entry := range index.Results {
+		spell, err := s.fetchSpell(ctx, entry.Index)
+		if err != nil {
+			return err
+		}
+
+		select {
+		case out <- spell:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		spells = append(spells, spell)
+	}
+
+	s.saveCache(spells)
+	return nil
+}
+
+type dnd5eSpellIndex struct {
+	Results []struct {
+		Index string `json:"index"`
+	} `json:"results"`
+}
+
+type dnd5eSpell struct {
+	Name        string `json:"name"`
+	Level       int    `json:"level"`
+	School      struct {
+		Name string `json:"name"`
+	} `json:"school"`
+	CastingTime   string `json:"casting_time"`
+	Range         string `json:"range"`
+	Duration      string `json:"duration"`
+	Ritual        bool   `json:"ritual"`
+	Concentration bool   `json:"concentration"`
+	Classes       []struct {
+		Name string `json:"name"`
+	} `json:"classes"`
+	Desc []string `json:"desc"`
+}
+
+// fetchSpell fetches and converts a single spell's detail page.
+func (s *dnd5eAPISource) fetchSpell(ctx context.Context, index string) (Spell, error) {
+	var raw dnd5eSpell
+	if err := s.getJSON(ctx, s.baseURL+"/spells/"+index, &raw); err != nil {
+		return Spell{}, err
+	}
+
+	classes := make([]string, len(raw.Classes))
+	for i, c := range raw.Classes {
+		classes[i] = c.Name
+	}
+
+	return Spell{
+		Name:          raw.Name,
+		Level:         raw.Level,
+		School:        raw.School.Name,
+		CastingTime:   raw.CastingTime,
+		Range:         raw.Range,
+		Duration:      raw.Duration,
+		Ritual:        raw.Ritual,
+		Concentration: raw.Concentration,
+		Classes:       classes,
+		Description:   strings.Join(raw.Desc, "\n\n"),
+	}, nil
+}
+
+func (s *dnd5eAPISource) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching %s: status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func dnd5eCachePath() string {
+	dir := os.Getenv("XDG_CACHE_HOME")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".cache")
+	}
+	return filepath.Join(dir, "banshie", "dnd5eapi.json")
+}
+
+func (s *dnd5eAPISource) loadCache() ([]Spell, error) {
+	data, err := os.ReadFile(dnd5eCachePath())
+	if err != nil {
+		return nil, err
+	}
+
+	var spells []Spell
+	if err := json.Unmarshal(data, &spells); err != nil {
+		return nil, err
+	}
+	return spells, nil
+}
+
+func (s *dnd5eAPISource) saveCache(spells []Spell) {
+	path := dnd5eCachePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+
+	data, err := json.Marshal(spells)
+	if err != nil {
+		return
+	}
+	os.WriteFile(path, data, 0o644)
+}
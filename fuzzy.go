@@ -0,0 +1,82 @@
+package main
+
+import "strings"
+
+// Scoring weights used by fuzzyScore. Tuned so consecutive and
+// word-boundary matches dominate over scattered single-rune hits, and gaps
+// between matches are penalised per skipped rune, so a match with long gaps
+// can score below fuzzyThreshold and be dropped.
+const (
+	scorePerMatch     = 16
+	scoreConsecutive  = 8
+	scoreWordBoundary = 12
+	scoreCaseMatch    = 4
+	penaltyPerGap     = 4
+
+	// fuzzyThreshold is the minimum score a candidate needs to be kept.
+	// Anything below it is considered noise rather than a real match.
+	fuzzyThreshold = 1
+)
+
+// match is the result of scoring a candidate string against a fuzzy search
+// pattern: its overall quality score and the rune indices within the
+// candidate that were actually matched, so callers can highlight them.
+type match struct {
+	score   int
+	indices []int
+}
+
+// fuzzyScore scores candidate against pattern with a Smith-Waterman-style
+// left-to-right alignment: every pattern rune must be found in order inside
+// candidate, consecutive matches and word-boundary matches are rewarded,
+// case-sensitive matches get a small bonus, and runs of skipped runes
+// between matches are penalised. A pattern that can't be fully matched
+// scores zero with no indices.
+func fuzzyScore(candidate, pattern string) match {
+	if pattern == "" {
+		return match{}
+	}
+
+	cRunes := []rune(candidate)
+	pRunes := []rune(pattern)
+	lowerC := []rune(strings.ToLower(candidate))
+	lowerP := []rune(strings.ToLower(pattern))
+
+	indices := make([]int, 0, len(pRunes))
+	score := 0
+	pi := 0
+	gap := 0
+
+	for ci := 0; ci < len(lowerC) && pi < len(lowerP); ci++ {
+		if lowerC[ci] != lowerP[pi] {
+			if len(indices) > 0 {
+				gap++
+			}
+			continue
+		}
+
+		points := scorePerMatch
+		if len(indices) > 0 && indices[len(indices)-1] == ci-1 {
+			points += scoreConsecutive
+		}
+		if ci == 0 || cRunes[ci-1] == ' ' || cRunes[ci-1] == '-' {
+			points += scoreWordBoundary
+		}
+		if cRunes[ci] == pRunes[pi] {
+			points += scoreCaseMatch
+		}
+		points -= gap * penaltyPerGap
+		gap = 0
+
+		score += points
+		indices = append(indices, ci)
+		pi++
+	}
+
+	// Not every pattern rune was found in candidate: no match at all.
+	if pi != len(pRunes) {
+		return match{}
+	}
+
+	return match{score: score, indices: indices}
+}
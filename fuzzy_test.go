@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestFuzzyScoreNoMatch(t *testing.T) {
+	if m := fuzzyScore("Fireball", "xyz"); m.score != 0 || m.indices != nil {
+		t.Fatalf("expected zero-value match for unmatched pattern, got %+v", m)
+	}
+}
+
+func TestFuzzyScoreEmptyPattern(t *testing.T) {
+	if m := fuzzyScore("Fireball", ""); m.score != 0 || m.indices != nil {
+		t.Fatalf("expected zero-value match for empty pattern, got %+v", m)
+	}
+}
+
+func TestFuzzyScoreRewardsConsecutiveAndWordBoundary(t *testing.T) {
+	consecutive := fuzzyScore("Fireball", "Fire")
+	scattered := fuzzyScore("Fireball", "Fel")
+
+	if consecutive.score <= scattered.score {
+		t.Fatalf("consecutive/word-boundary match should outscore a scattered one: %d vs %d",
+			consecutive.score, scattered.score)
+	}
+}
+
+func TestFuzzyScoreRanksWordBoundaryAboveMidword(t *testing.T) {
+	boundary := fuzzyScore("Wall of Fire", "Wall")
+	midword := fuzzyScore("Firewall", "wall")
+
+	if boundary.score <= midword.score {
+		t.Fatalf("a word-boundary match should outscore an identical match mid-word: %d vs %d",
+			boundary.score, midword.score)
+	}
+}
+
+func TestFuzzyScoreIndicesMatchPattern(t *testing.T) {
+	m := fuzzyScore("Fireball", "Frbl")
+	if len(m.indices) != 4 {
+		t.Fatalf("expected 4 matched indices, got %d (%v)", len(m.indices), m.indices)
+	}
+	for i := 1; i < len(m.indices); i++ {
+		if m.indices[i] <= m.indices[i-1] {
+			t.Fatalf("indices must be strictly increasing, got %v", m.indices)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+)
+
+// historySize caps how many queries are kept in the ring buffer and
+// persisted to disk.
+const historySize = 1000
+
+// history is a ring buffer of previously submitted search queries,
+// persisted to disk so it survives across sessions.
+type history struct {
+	entries []string
+	pos     int
+	path    string
+}
+
+// newHistory loads the history file from $XDG_STATE_HOME/banshie/history,
+// falling back to an empty history if none exists yet or it can't be read.
+func newHistory() *history {
+	h := &history{path: historyPath()}
+	h.load()
+	h.pos = len(h.entries)
+	return h
+}
+
+func historyPath() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".local", "state")
+	}
+	return filepath.Join(dir, "banshie", "history")
+}
+
+func (h *history) load() {
+	f, err := os.Open(h.path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+}
+
+// Add appends a query to the history and persists it, skipping consecutive
+// duplicates and resetting the navigation cursor to the newest entry.
+func (h *history) Add(query string) {
+	if query == "" {
+		return
+	}
+	if len(h.entries) > 0 && h.entries[len(h.entries)-1] == query {
+		h.pos = len(h.entries)
+		return
+	}
+
+	h.entries = append(h.entries, query)
+	if len(h.entries) > historySize {
+		h.entries = h.entries[len(h.entries)-historySize:]
+	}
+	h.pos = len(h.entries)
+
+	h.save()
+}
+
+func (h *history) save() {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return
+	}
+	f, err := os.Create(h.path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	for _, e := range h.entries {
+		w.WriteString(e)
+		w.WriteString("\n")
+	}
+	w.Flush()
+}
+
+// Prev moves one step back in history, returning the entry found there.
+// ok is false once the oldest entry has already been reached.
+func (h *history) Prev() (entry string, ok bool) {
+	if h.pos <= 0 {
+		return "", false
+	}
+	h.pos--
+	return h.entries[h.pos], true
+}
+
+// Next moves one step forward in history. Moving past the newest entry
+// returns an empty string, so callers can clear the input field back to a
+// blank query.
+func (h *history) Next() (entry string, ok bool) {
+	if h.pos >= len(h.entries) {
+		return "", false
+	}
+	h.pos++
+	if h.pos == len(h.entries) {
+		return "", true
+	}
+	return h.entries[h.pos], true
+}
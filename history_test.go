@@ -0,0 +1,81 @@
+package main
+
+import "testing"
+
+func TestHistoryAddSkipsConsecutiveDuplicates(t *testing.T) {
+	h := &history{path: t.TempDir() + "/history"}
+
+	h.Add("fireball")
+	h.Add("fireball")
+	h.Add("magic missile")
+
+	if len(h.entries) != 2 {
+		t.Fatalf("expected consecutive duplicate to be skipped, got entries %v", h.entries)
+	}
+}
+
+func TestHistoryAddIgnoresEmptyQuery(t *testing.T) {
+	h := &history{path: t.TempDir() + "/history"}
+
+	h.Add("")
+	if len(h.entries) != 0 {
+		t.Fatalf("expected empty query to be ignored, got entries %v", h.entries)
+	}
+}
+
+func TestHistoryAddTrimsToHistorySize(t *testing.T) {
+	h := &history{path: t.TempDir() + "/history"}
+
+	for i := 0; i < historySize+10; i++ {
+		h.Add(string(rune('a' + i%26)))
+	}
+
+	if len(h.entries) != historySize {
+		t.Fatalf("expected entries trimmed to %d, got %d", historySize, len(h.entries))
+	}
+}
+
+func TestHistoryPrevAndNext(t *testing.T) {
+	h := &history{path: t.TempDir() + "/history"}
+	h.Add("fireball")
+	h.Add("magic missile")
+
+	entry, ok := h.Prev()
+	if !ok || entry != "magic missile" {
+		t.Fatalf("expected \"magic missile\", got %q ok=%v", entry, ok)
+	}
+
+	entry, ok = h.Prev()
+	if !ok || entry != "fireball" {
+		t.Fatalf("expected \"fireball\", got %q ok=%v", entry, ok)
+	}
+
+	if _, ok = h.Prev(); ok {
+		t.Fatalf("expected Prev to fail once the oldest entry is reached")
+	}
+
+	entry, ok = h.Next()
+	if !ok || entry != "magic missile" {
+		t.Fatalf("expected \"magic missile\" moving forward, got %q ok=%v", entry, ok)
+	}
+
+	entry, ok = h.Next()
+	if !ok || entry != "" {
+		t.Fatalf("expected Next past the newest entry to clear back to empty, got %q ok=%v", entry, ok)
+	}
+}
+
+func TestHistorySaveAndLoadRoundTrip(t *testing.T) {
+	path := t.TempDir() + "/history"
+
+	h := &history{path: path}
+	h.Add("fireball")
+	h.Add("magic missile")
+
+	loaded := &history{path: path}
+	loaded.load()
+
+	if len(loaded.entries) != 2 || loaded.entries[0] != "fireball" || loaded.entries[1] != "magic missile" {
+		t.Fatalf("expected entries to round-trip through disk, got %v", loaded.entries)
+	}
+}
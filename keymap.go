@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/gdamore/tcell"
+)
+
+// keymap resolves key events to named actions using the chord->action
+// bindings loaded from config, buffering a short sequence so two-key
+// chords like Vim's "gg" can be distinguished from a bare "g".
+type keymap struct {
+	bindings map[string]action
+	pending  string
+}
+
+// newKeymap builds a keymap from the chord->action names loaded in cfg.
+func newKeymap(cfg *config) *keymap {
+	bindings := make(map[string]action, len(cfg.Keybindings))
+	for chord, act := range cfg.Keybindings {
+		bindings[chord] = action(act)
+	}
+	return &keymap{bindings: bindings}
+}
+
+// Resolve returns the action bound to event. ok is false while a
+// multi-key sequence is still being buffered, or if the chord (and
+// whatever preceded it) isn't bound to anything.
+func (k *keymap) Resolve(event *tcell.EventKey) (act action, ok bool) {
+	chord := chordName(event)
+	if chord == "" {
+		k.pending = ""
+		return "", false
+	}
+
+	combined := k.pending + chord
+sequences:
+	for bound := range k.bindings {
+		// a named key like "Right" is a single chord, not a sequence that
+		// starts with "R"
+		for _, name := range namedKeys {
+			if bound == name {
+				continue sequences
+			}
+		}
+		if len(bound) > len(combined) && bound[:len(combined)] == combined {
+			k.pending = combined
+			return "", false
+		}
+	}
+
+	if act, bound := k.bindings[combined]; bound {
+		k.pending = ""
+		return act, true
+	}
+
+	k.pending = ""
+	if act, bound := k.bindings[chord]; bound {
+		return act, true
+	}
+	return "", false
+}
+
+// namedKeys renders the non-printable keys used in config.toml chords.
+var namedKeys = map[tcell.Key]string{
+	tcell.KeyUp:    "Up",
+	tcell.KeyDown:  "Down",
+	tcell.KeyLeft:  "Left",
+	tcell.KeyRight: "Right",
+	tcell.KeyEnter: "Enter",
+	tcell.KeyEsc:   "Esc",
+	tcell.KeyTab:   "Tab",
+	tcell.KeyCtrlH: "Ctrl-H",
+	tcell.KeyCtrlJ: "Ctrl-J",
+	tcell.KeyCtrlK: "Ctrl-K",
+	tcell.KeyCtrlL: "Ctrl-L",
+	tcell.KeyCtrlD: "Ctrl-D",
+	tcell.KeyCtrlP: "Ctrl-P",
+	tcell.KeyCtrlN: "Ctrl-N",
+}
+
+// chordName renders event as the chord string used in config.toml, e.g.
+// "Ctrl-H" or "g". tcell delivers a shifted letter as the bare uppercase
+// rune with ModShift already stripped, so an uppercase letter is rendered
+// as itself (e.g. "R") rather than a synthesized "Shift-" chord; ModShift
+// only ever turns up set on keys that don't carry case, like Tab.
+func chordName(event *tcell.EventKey) string {
+	if name, ok := namedKeys[event.Key()]; ok {
+		return name
+	}
+	if event.Rune() != 0 {
+		if event.Modifiers()&tcell.ModShift != 0 && !unicode.IsUpper(event.Rune()) {
+			return fmt.Sprintf("Shift-%c", event.Rune())
+		}
+		return string(event.Rune())
+	}
+	return ""
+}
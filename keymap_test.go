@@ -0,0 +1,95 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func runeEvent(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+func TestKeymapResolveSingleChord(t *testing.T) {
+	k := &keymap{bindings: map[string]action{"p": actionTogglePrepared}}
+
+	act, ok := k.Resolve(runeEvent('p'))
+	if !ok || act != actionTogglePrepared {
+		t.Fatalf("expected actionTogglePrepared, got %q ok=%v", act, ok)
+	}
+}
+
+func TestKeymapResolveTwoKeySequence(t *testing.T) {
+	k := &keymap{bindings: map[string]action{
+		"gg": actionFocusList,
+		"g":  actionScrollUp,
+	}}
+
+	// The first "g" is a prefix of "gg", so it must buffer rather than
+	// immediately firing the shorter "g" binding.
+	act, ok := k.Resolve(runeEvent('g'))
+	if ok {
+		t.Fatalf("expected first 'g' to buffer as pending, got action %q", act)
+	}
+	if k.pending != "g" {
+		t.Fatalf("expected pending chord %q, got %q", "g", k.pending)
+	}
+
+	act, ok = k.Resolve(runeEvent('g'))
+	if !ok || act != actionFocusList {
+		t.Fatalf("expected actionFocusList after completing 'gg', got %q ok=%v", act, ok)
+	}
+	if k.pending != "" {
+		t.Fatalf("expected pending to be cleared after a resolved chord, got %q", k.pending)
+	}
+}
+
+func TestKeymapResolveUnboundChordClearsPending(t *testing.T) {
+	k := &keymap{bindings: map[string]action{"gg": actionFocusList}}
+
+	k.Resolve(runeEvent('g'))
+	act, ok := k.Resolve(runeEvent('x'))
+	if ok {
+		t.Fatalf("expected no action for an unbound chord, got %q", act)
+	}
+	if k.pending != "" {
+		t.Fatalf("expected pending to be cleared after an unbound chord, got %q", k.pending)
+	}
+}
+
+func TestKeymapResolveUnboundKey(t *testing.T) {
+	k := &keymap{bindings: map[string]action{"p": actionTogglePrepared}}
+
+	act, ok := k.Resolve(runeEvent('z'))
+	if ok {
+		t.Fatalf("expected no action for an unbound key, got %q", act)
+	}
+}
+
+func TestChordNameShiftedLetter(t *testing.T) {
+	// tcell delivers a shifted letter as the bare uppercase rune with
+	// ModShift already stripped, so the chord must be "R", not "Shift-R".
+	event := tcell.NewEventKey(tcell.KeyRune, 'R', tcell.ModNone)
+	if got := chordName(event); got != "R" {
+		t.Fatalf("expected chord %q for a shifted letter, got %q", "R", got)
+	}
+}
+
+func TestKeymapResolveShiftedLetter(t *testing.T) {
+	k := &keymap{bindings: map[string]action{"R": actionLongRest}}
+
+	act, ok := k.Resolve(tcell.NewEventKey(tcell.KeyRune, 'R', tcell.ModNone))
+	if !ok || act != actionLongRest {
+		t.Fatalf("expected actionLongRest for shifted R, got %q ok=%v", act, ok)
+	}
+}
+
+func TestNewKeymapCopiesConfigBindings(t *testing.T) {
+	cfg := &config{Keybindings: map[string]string{"p": string(actionTogglePrepared)}}
+	k := newKeymap(cfg)
+
+	act, ok := k.Resolve(runeEvent('p'))
+	if !ok || act != actionTogglePrepared {
+		t.Fatalf("expected actionTogglePrepared from loaded config, got %q ok=%v", act, ok)
+	}
+}
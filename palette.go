@@ -0,0 +1,80 @@
+package main
+
+import (
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+// paletteActions lists every named, invocable action in a fixed order, used
+// to fuzzy-match the command palette's input against action names.
+var paletteActions = []action{
+	actionFocusList,
+	actionFocusDetails,
+	actionScrollUp,
+	actionScrollDown,
+	actionClearInput,
+	actionTogglePrepared,
+	actionOpenPalette,
+	actionSwitchFocus,
+	actionSelectSpell,
+	actionLongRest,
+	actionCycleSource,
+	actionExpendSlotPrefix + "1",
+	actionExpendSlotPrefix + "2",
+	actionExpendSlotPrefix + "3",
+	actionExpendSlotPrefix + "4",
+	actionExpendSlotPrefix + "5",
+	actionExpendSlotPrefix + "6",
+	actionExpendSlotPrefix + "7",
+	actionExpendSlotPrefix + "8",
+	actionExpendSlotPrefix + "9",
+}
+
+// getPalette returns the ":"-triggered command palette: a modal input
+// field that fuzzy-matches its text against action names. Whenever the
+// field is done (Enter, Esc, Tab) it calls onCancel to close the palette;
+// on Enter it then invokes onAction with the best match, if any.
+func getPalette(onAction func(action), onCancel func()) *tview.InputField {
+	input := tview.NewInputField().
+		SetLabel(": ").
+		SetFieldBackgroundColor(tview.Styles.PrimitiveBackgroundColor)
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		text := input.GetText()
+		input.SetText("")
+		onCancel()
+
+		if key != tcell.KeyEnter {
+			return
+		}
+		if act, ok := matchAction(text); ok {
+			onAction(act)
+		}
+	})
+	return input
+}
+
+// matchAction fuzzy-matches query against every known action name and
+// returns the best-scoring one, if any clears fuzzyThreshold.
+func matchAction(query string) (action, bool) {
+	var best action
+	bestScore := fuzzyThreshold - 1
+	for _, act := range paletteActions {
+		if m := fuzzyScore(string(act), query); m.score > bestScore {
+			best, bestScore = act, m.score
+		}
+	}
+	return best, bestScore >= fuzzyThreshold
+}
+
+// modal centers p in a box of the given size, used to overlay the command
+// palette over the main layout.
+func modal(p tview.Primitive, width, height int) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 1, true).
+			AddItem(nil, 0, 1, false), width, 1, true).
+		AddItem(nil, 0, 1, false)
+}
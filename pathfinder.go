@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// pathfinderSource scrapes spell data from the Pathfinder 2e Archives of
+// Nethys. AoN has no public REST API of its own, but its search box is
+// backed by a public Elasticsearch index, which this queries directly and
+// pages through "category": "spell" documents.
+type pathfinderSource struct {
+	searchURL string
+	client    *http.Client
+	pageSize  int
+}
+
+// newPathfinderSource returns a source backed by AoN's Elasticsearch
+// index.
+func newPathfinderSource() *pathfinderSource {
+	return &pathfinderSource{
+		searchURL: "https://elasticsearch.aonprd.com/aon/_search",
+		client:    &http.Client{Timeout: 10 * time.Second},
+		pageSize:  100,
+	}
+}
+
+func (s *pathfinderSource) Name() string { return "Pathfinder 2e" }
+
+func (s *pathfinderSource) Load(ctx context.Context) ([]Spell, error) {
+	out := make(chan Spell)
+	errChan := make(chan error, 1)
+
+	go func() {
+		errChan <- s.Stream(ctx, out)
+		close(out)
+	}()
+
+	var spells []Spell
+	for spell := range out {
+		spells = append(spells, spell)
+	}
+	return spells, <-errChan
+}
+
+// Stream pages through the spell documents in AoN's index from/size style,
+// emitting each spell as soon as its page has been parsed, and stopping
+// once a page comes back empty.
+func (s *pathfinderSource) Stream(ctx context.Context, out chan<- Spell) error {
+	for from := 0; ; from += s.pageSize {
+		hits, err := s.fetchPage(ctx, from)
+		if err != nil {
+			return err
+		}
+		if len(hits) == 0 {
+			return nil
+		}
+
+		for _, hit := range hits {
+			select {
+			case out <- hit.Source.toSpell():
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+type aonSearchRequest struct {
+	From  int            `json:"from"`
+	Size  int            `json:"size"`
+	Query aonSearchQuery `json:"query"`
+}
+
+type aonSearchQuery struct {
+	Term aonSearchTerm `json:"term"`
+}
+
+type aonSearchTerm struct {
+	Category string `json:"category"`
+}
+
+type aonSearchResponse struct {
+	Hits struct {
+		Hits []aonHit `json:"hits"`
+	} `json:"hits"`
+}
+
+type aonHit struct {
+	Source aonSpellDoc `json:"_source"`
+}
+
+// aonSpellDoc mirrors the subset of fields AoN's spell documents expose
+// that map onto Spell.
+type aonSpellDoc struct {
+	Name       string   `json:"name"`
+	Level      int      `json:"level"`
+	School     string   `json:"school"`
+	Cast       string   `json:"cast"`
+	Range      string   `json:"range"`
+	Duration   string   `json:"duration"`
+	Traditions []string `json:"tradition"`
+	Text       string   `json:"text"`
+}
+
+func (d aonSpellDoc) toSpell() Spell {
+	return Spell{
+		Name:        d.Name,
+		Level:       d.Level,
+		School:      d.School,
+		CastingTime: d.Cast,
+		Range:       d.Range,
+		Duration:    d.Duration,
+		Classes:     d.Traditions,
+		Description: d.Text,
+	}
+}
+
+func (s *pathfinderSource) fetchPage(ctx context.Context, from int) ([]aonHit, error) {
+	body, err := json.Marshal(aonSearchRequest{
+		From:  from,
+		Size:  s.pageSize,
+		Query: aonSearchQuery{Term: aonSearchTerm{Category: "spell"}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.searchURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying archives of nethys: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("querying archives of nethys: status %d", resp.StatusCode)
+	}
+
+	var parsed aonSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("parsing archives of nethys response: %w", err)
+	}
+	return parsed.Hits.Hits, nil
+}
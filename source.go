@@ -0,0 +1,41 @@
+package main
+
+import "context"
+
+// SpellSource is a pluggable provider of spell data. Local bundles, remote
+// APIs, and scrapers can all implement it and be combined so a DM running
+// a multi-system game can search across all of them from one UI.
+type SpellSource interface {
+	// Name identifies the source for status messages and the source
+	// filter chip, e.g. "SRD" or "Pathfinder 2e".
+	Name() string
+	// Load returns every spell the source has to offer.
+	Load(ctx context.Context) ([]Spell, error)
+	// Stream behaves like Load but delivers each spell on out as soon as
+	// it's available, so callers can report incremental progress.
+	Stream(ctx context.Context, out chan<- Spell) error
+}
+
+// defaultSourceNames is used when config doesn't list any sources.
+var defaultSourceNames = []string{"srd"}
+
+// buildSources resolves configured source names into SpellSource
+// instances, skipping any name that isn't recognised.
+func buildSources(names []string, bundledPath string) []SpellSource {
+	if len(names) == 0 {
+		names = defaultSourceNames
+	}
+
+	var sources []SpellSource
+	for _, name := range names {
+		switch name {
+		case "srd":
+			sources = append(sources, newBundledSource(bundledPath))
+		case "dnd5eapi":
+			sources = append(sources, newDnd5eAPISource())
+		case "pathfinder2e":
+			sources = append(sources, newPathfinderSource())
+		}
+	}
+	return sources
+}
@@ -0,0 +1,20 @@
+package main
+
+// Spell describes a single spell entry as loaded from a data source.
+type Spell struct {
+	Name          string   `json:"name"`
+	Level         int      `json:"level"`
+	School        string   `json:"school"`
+	CastingTime   string   `json:"casting_time"`
+	Range         string   `json:"range"`
+	Components    string   `json:"components"`
+	Duration      string   `json:"duration"`
+	Ritual        bool     `json:"ritual"`
+	Concentration bool     `json:"concentration"`
+	Classes       []string `json:"classes"`
+	Description   string   `json:"description"`
+
+	// Source is the name of the SpellSource this spell was loaded from,
+	// e.g. "SRD" or "Pathfinder 2e". Set by loadAllData, not persisted.
+	Source string `json:"-"`
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// slotCount tracks how many of a level's spell slots are currently
+// available versus the character's maximum.
+type slotCount struct {
+	Current int `json:"current"`
+	Max     int `json:"max"`
+}
+
+// spellbook tracks a single character's prepared spells, slot usage, and
+// current concentration, persisted to disk between sessions.
+type spellbook struct {
+	Name          string            `json:"name"`
+	Prepared      map[string]bool   `json:"prepared"`
+	Slots         map[int]slotCount `json:"slots"`
+	Concentrating string            `json:"concentrating"`
+}
+
+// characterName returns the active character's name, used to pick which
+// spellbook file to load: $BANSHIE_CHARACTER if set, otherwise "default".
+func characterName() string {
+	if name := os.Getenv("BANSHIE_CHARACTER"); name != "" {
+		return name
+	}
+	return "default"
+}
+
+// defaultSlotTable seeds a new spellbook with a full caster's level-20
+// slot progression (4/3/3/3/3/2/2/1/1) as a usable starting point, since
+// banshie doesn't track a character's class or level to derive one
+// precisely. A DM edits the persisted JSON to match the actual character.
+var defaultSlotTable = map[int]slotCount{
+	1: {Current: 4, Max: 4},
+	2: {Current: 3, Max: 3},
+	3: {Current: 3, Max: 3},
+	4: {Current: 3, Max: 3},
+	5: {Current: 3, Max: 3},
+	6: {Current: 2, Max: 2},
+	7: {Current: 2, Max: 2},
+	8: {Current: 1, Max: 1},
+	9: {Current: 1, Max: 1},
+}
+
+// newSpellbook returns a fresh spellbook for the named character, seeded
+// with defaultSlotTable.
+func newSpellbook(name string) *spellbook {
+	slots := make(map[int]slotCount, len(defaultSlotTable))
+	for level, s := range defaultSlotTable {
+		slots[level] = s
+	}
+
+	return &spellbook{
+		Name:     name,
+		Prepared: make(map[string]bool),
+		Slots:    slots,
+	}
+}
+
+func spellbookPath(name string) string {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".local", "share")
+	}
+	return filepath.Join(dir, "banshie", "characters", name+".json")
+}
+
+// loadSpellbook loads a character's spellbook from disk, returning a fresh
+// one if none is saved yet or it can't be read.
+func loadSpellbook(name string) *spellbook {
+	data, err := os.ReadFile(spellbookPath(name))
+	if err != nil {
+		return newSpellbook(name)
+	}
+
+	book := newSpellbook(name)
+	if err := json.Unmarshal(data, book); err != nil {
+		return newSpellbook(name)
+	}
+	return book
+}
+
+// loadSpellbookAsync loads the named character's spellbook and delivers it
+// on ch, mirroring how loadAllData streams spell data in on startup.
+func loadSpellbookAsync(name string, ch chan<- *spellbook) {
+	ch <- loadSpellbook(name)
+}
+
+// Save persists the spellbook to
+// $XDG_DATA_HOME/banshie/characters/<name>.json.
+func (b *spellbook) Save() error {
+	path := spellbookPath(b.Name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// TogglePrepared flips whether name is prepared.
+func (b *spellbook) TogglePrepared(name string) {
+	b.Prepared[name] = !b.Prepared[name]
+	b.Save()
+}
+
+// IsPrepared reports whether name is currently prepared.
+func (b *spellbook) IsPrepared(name string) bool {
+	return b.Prepared[name]
+}
+
+// ExpendSlot uses one slot of the given level, returning false if none
+// remain.
+func (b *spellbook) ExpendSlot(level int) bool {
+	s := b.Slots[level]
+	if s.Current <= 0 {
+		return false
+	}
+	s.Current--
+	b.Slots[level] = s
+	b.Save()
+	return true
+}
+
+// SlotsAt returns the current/max slot count for level.
+func (b *spellbook) SlotsAt(level int) slotCount {
+	return b.Slots[level]
+}
+
+// SetConcentration records the spell currently being concentrated on, or
+// clears it when name is empty.
+func (b *spellbook) SetConcentration(name string) {
+	b.Concentrating = name
+	b.Save()
+}
+
+// LongRest resets every slot back to its maximum and clears concentration.
+func (b *spellbook) LongRest() {
+	for level, s := range b.Slots {
+		s.Current = s.Max
+		b.Slots[level] = s
+	}
+	b.Concentrating = ""
+	b.Save()
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+// syntaxRule is a single highlighting rule: any text matching Pattern in a
+// spell description is recolored (and optionally bolded). Inspired by phi's
+// languageInfo *cfg.LanguageSyntaxConfig. The rules in use are the built-in
+// defaultSyntaxRules; none are read from config.toml.
+type syntaxRule struct {
+	Pattern *regexp.Regexp
+	Color   tcell.Color
+	Bold    bool
+}
+
+// defaultSyntaxRules ships highlighting for the phrases that show up in
+// almost every 5e spell description: dice expressions, saving throws,
+// damage types, durations, and range/area phrases.
+var defaultSyntaxRules = []syntaxRule{
+	{regexp.MustCompile(`\d+d\d+(\s*[+-]\s*\d+)?`), tcell.ColorYellow, true},
+	{regexp.MustCompile(`DC \d+ \w+`), tcell.ColorOrange, true},
+	{regexp.MustCompile(`(?i)\b(fire|cold|radiant|necrotic|lightning|thunder|poison|acid|psychic|force|bludgeoning|piercing|slashing)\b`), tcell.ColorFuchsia, false},
+	{regexp.MustCompile(`(?i)\b(instantaneous|concentration|\d+ (round|minute|hour|day)s?)\b`), tcell.ColorAqua, false},
+	{regexp.MustCompile(`(?i)\b(\d+-foot (radius|cone|line|cube|sphere)|self|touch|sight|unlimited)\b`), tcell.ColorGreen, false},
+}
+
+// highlightDescription tokenizes text against rules once and emits tview
+// color tags around every match, leaving everything else untouched. When
+// two rules' matches overlap, the earlier and longer one wins.
+func highlightDescription(text string, rules []syntaxRule) string {
+	type span struct {
+		start, end int
+		rule       syntaxRule
+	}
+
+	var spans []span
+	for _, r := range rules {
+		for _, loc := range r.Pattern.FindAllStringIndex(text, -1) {
+			spans = append(spans, span{loc[0], loc[1], r})
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i].start != spans[j].start {
+			return spans[i].start < spans[j].start
+		}
+		return spans[i].end > spans[j].end
+	})
+
+	var out strings.Builder
+	pos := 0
+	for _, s := range spans {
+		if s.start < pos {
+			continue
+		}
+		out.WriteString(text[pos:s.start])
+		out.WriteString(colorTag(s.rule))
+		out.WriteString(text[s.start:s.end])
+		out.WriteString("[-:-:-]")
+		pos = s.end
+	}
+	out.WriteString(text[pos:])
+	return out.String()
+}
+
+func colorTag(rule syntaxRule) string {
+	attrs := "-"
+	if rule.Bold {
+		attrs = "b"
+	}
+	return fmt.Sprintf("[#%06x:-:%s]", rule.Color.Hex()&0xffffff, attrs)
+}
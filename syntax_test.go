@@ -0,0 +1,60 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestHighlightDescriptionWrapsMatch(t *testing.T) {
+	rules := []syntaxRule{
+		{regexp.MustCompile(`\d+d\d+`), tcell.ColorYellow, true},
+	}
+
+	got := highlightDescription("You take 2d6 fire damage.", rules)
+	want := "You take " + colorTag(rules[0]) + "2d6" + "[-:-:-]" + " fire damage."
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestHighlightDescriptionNoMatch(t *testing.T) {
+	rules := []syntaxRule{
+		{regexp.MustCompile(`\d+d\d+`), tcell.ColorYellow, true},
+	}
+
+	text := "Nothing numeric here."
+	if got := highlightDescription(text, rules); got != text {
+		t.Fatalf("expected unchanged text, got %q", got)
+	}
+}
+
+func TestHighlightDescriptionOverlapEarlierLongerWins(t *testing.T) {
+	// Both rules match starting at index 0, but the first is longer, so it
+	// should win and the second rule's overlapping match should be dropped
+	// entirely rather than double-tagging or truncating it.
+	rules := []syntaxRule{
+		{regexp.MustCompile(`fireball`), tcell.ColorYellow, true},
+		{regexp.MustCompile(`fire`), tcell.ColorFuchsia, false},
+	}
+
+	got := highlightDescription("fireball deals damage", rules)
+	want := colorTag(rules[0]) + "fireball" + "[-:-:-]" + " deals damage"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestHighlightDescriptionAdjacentNonOverlappingMatches(t *testing.T) {
+	rules := []syntaxRule{
+		{regexp.MustCompile(`\d+d\d+`), tcell.ColorYellow, true},
+		{regexp.MustCompile(`(?i)fire`), tcell.ColorFuchsia, false},
+	}
+
+	got := highlightDescription("2d6 fire damage", rules)
+	want := colorTag(rules[0]) + "2d6" + "[-:-:-]" + " " + colorTag(rules[1]) + "fire" + "[-:-:-]" + " damage"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -21,6 +23,19 @@ type App struct {
 	spells           *[]Spell
 	dataChan         chan []Spell
 	statusChan       chan string
+	history          *history
+	completer        *tabCompleter
+	ghost            string
+	suppressChange   bool
+	config           *config
+	keymap           *keymap
+	pages            *tview.Pages
+	palette          *tview.InputField
+	spellbook        *spellbook
+	spellbookChan    chan *spellbook
+	sources          []SpellSource
+	sourceFilter     int
+	sourceChip       *tview.TextView
 }
 
 // Instantiate a new app ready to run
@@ -37,9 +52,26 @@ func newApp() *App {
 	ui.widebox = getWideBox()
 	ui.dataChan = make(chan []Spell)
 	ui.statusChan = make(chan string)
+	ui.history = newHistory()
+	ui.completer = newTabCompleter(ui.completionCandidates)
+	ui.config = loadConfig()
+	ui.keymap = newKeymap(ui.config)
+	ui.palette = getPalette(ui.dispatch, ui.closePalette)
+	ui.spellbookChan = make(chan *spellbook)
+	ui.sources = buildSources(ui.config.Sources, defaultBundledPath)
+	ui.sourceFilter = -1
+	ui.sourceChip = getSourceChip()
+	ui.setSourceChip()
 	go ui.waitForData()
 	go ui.waitForStatuses()
-	go loadAllData(ui.dataChan, ui.statusChan)
+	go ui.waitForSpellbook()
+	go loadAllData(ui.sources, ui.dataChan, ui.statusChan)
+	go loadSpellbookAsync(characterName(), ui.spellbookChan)
+
+	// keys specific to the search input (history, completion) that the
+	// global handler passes through reach the input's own handler; while the
+	// user is typing, handleInput runs it first, ahead of the global bindings
+	ui.input.SetInputCapture(ui.handleInputFieldInput)
 
 	// set the global input handler
 	ui.app.SetInputCapture(ui.handleInput)
@@ -52,9 +84,13 @@ func newApp() *App {
 			AddItem(ui.widebox.grid, 0, 7, false), 0, 1, false).
 		AddItem(tview.NewFlex().SetDirection(tview.FlexColumn).
 			AddItem(ui.input, 0, 3, false).
+			AddItem(ui.sourceChip, 20, 0, false).
 			AddItem(ui.statusBox, 0, 7, false), 1, 0, false)
 
-	app.SetRoot(root, true)
+	ui.pages = tview.NewPages().
+		AddPage("main", root, true, true).
+		AddPage("palette", modal(ui.palette, 60, 3), true, false)
+	app.SetRoot(ui.pages, true)
 
 	app.SetFocus(ui.input)
 	ui.focusList()
@@ -86,46 +122,185 @@ func (app *App) waitForStatuses() {
 	}
 }
 
-// The main app global input handler
+// Waits for the active character's spellbook to finish loading from disk,
+// then attaches it to the widebox's slots column and re-renders the list
+// so prepared markers show up.
+func (app *App) waitForSpellbook() {
+	book := <-app.spellbookChan
+	app.spellbook = book
+	app.widebox.SetSpellbook(book)
+	if app.spells != nil {
+		app.setSpells()
+	}
+	app.app.Draw()
+}
+
+// The main app global input handler. Resolves the event through app.keymap
+// and dispatches the bound action, if any; unbound events are passed
+// through to whichever primitive is focused.
 func (app *App) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	// if status exists, clear it
 	if app.getStatus() != "" {
 		app.setStatus("")
 	}
-	switch event.Key() {
-	case tcell.KeyEnter:
-		//ui.wideboxFakeFocus = true
+
+	// while typing in the search input, its own keys (Tab completion, Enter
+	// to accept, history) take precedence over the global bindings
+	if app.app.GetFocus() == app.input && !app.wideboxFakeFocus {
+		if event = app.handleInputFieldInput(event); event == nil {
+			return nil
+		}
+	}
+
+	// printable runes are only bindable while the user isn't actually
+	// typing into a text field, so e.g. "p" or "s" toggle an action while
+	// browsing but still type normally while searching or in the palette
+	if event.Key() == tcell.KeyRune && app.isTextEntryFocused() {
+		return event
+	}
+
+	if act, ok := app.keymap.Resolve(event); ok {
+		app.dispatch(act)
+		return nil
+	}
+	return event
+}
+
+// isTextEntryFocused reports whether the user is currently typing into a
+// text field: the command palette, or the search input while it's driving
+// the list (as opposed to the widebox fake-focus, which keeps real tview
+// focus on the input but stops treating keys as search text).
+func (app *App) isTextEntryFocused() bool {
+	if app.app.GetFocus() == app.palette {
+		return true
+	}
+	return app.app.GetFocus() == app.input && !app.wideboxFakeFocus
+}
+
+// dispatch runs the named action against the current app state. Shared
+// between the global keymap and the command palette.
+func (app *App) dispatch(act action) {
+	switch act {
+	case actionSelectSpell:
 		if spell := app.currentSelectedSpell(); spell != nil {
 			app.widebox.SetSpell(spell)
 		}
-	case tcell.KeyUp, tcell.KeyCtrlK:
+	case actionScrollUp:
 		if app.wideboxFakeFocus {
 			app.widebox.ScrollUp()
-			break
+			return
 		}
 		app.list.SetCurrentItem(app.list.GetCurrentItem() - 1)
-	case tcell.KeyCtrlJ, tcell.KeyDown:
+	case actionScrollDown:
 		if app.wideboxFakeFocus {
 			app.widebox.ScrollDown()
-			break
+			return
 		}
 		item := app.list.GetCurrentItem()
 		if item >= app.list.GetItemCount()-1 {
 			app.list.SetCurrentItem(0)
-			break
+			return
 		}
 		app.list.SetCurrentItem(item + 1)
-	// tcell.KeyCtrlBackspace doesn't exist for whatever reason
-	case tcell.KeyCtrlD:
+	case actionClearInput:
 		app.input.SetText("")
-	case tcell.KeyLeft, tcell.KeyCtrlH:
+	case actionFocusList:
 		app.focusList()
-	case tcell.KeyRight, tcell.KeyCtrlL:
+	case actionFocusDetails:
 		app.focusWideBox()
-	case tcell.KeyTab:
+	case actionSwitchFocus:
 		app.switchFocus()
+	case actionOpenPalette:
+		app.openPalette()
+	case actionTogglePrepared:
+		if app.spellbook == nil {
+			return
+		}
+		if spell := app.currentSelectedSpell(); spell != nil {
+			app.spellbook.TogglePrepared(spell.Name)
+			app.setSpells()
+		}
+	case actionLongRest:
+		if app.spellbook == nil {
+			return
+		}
+		app.spellbook.LongRest()
+		app.widebox.RefreshSlots()
+		app.setStatus("long rest: all slots restored")
+	case actionCycleSource:
+		app.cycleSource()
+	default:
+		if level, ok := parseExpendSlotAction(act); ok {
+			app.expendSlot(level)
+		}
 	}
-	return event
+}
+
+// parseExpendSlotAction extracts the slot level from an expend_slot_N
+// action name.
+func parseExpendSlotAction(act action) (int, bool) {
+	if !strings.HasPrefix(string(act), actionExpendSlotPrefix) {
+		return 0, false
+	}
+	level, err := strconv.Atoi(strings.TrimPrefix(string(act), actionExpendSlotPrefix))
+	if err != nil {
+		return 0, false
+	}
+	return level, true
+}
+
+// expendSlot uses one of the character's slots of the given level, showing
+// a status message if none remain. If the currently selected spell
+// requires concentration, casting it this way starts concentrating on it.
+func (app *App) expendSlot(level int) {
+	if app.spellbook == nil {
+		return
+	}
+	if !app.spellbook.ExpendSlot(level) {
+		app.setStatus(fmt.Sprintf("no level %d slots remaining", level))
+		return
+	}
+
+	if spell := app.currentSelectedSpell(); spell != nil && spell.Concentration {
+		app.spellbook.SetConcentration(spell.Name)
+	}
+	app.widebox.RefreshSlots()
+}
+
+// cycleSource advances the visible-source filter: -1 (all sources), then
+// each configured source in turn, then back to all.
+func (app *App) cycleSource() {
+	app.sourceFilter++
+	if app.sourceFilter >= len(app.sources) {
+		app.sourceFilter = -1
+	}
+	app.setSourceChip()
+	if app.spells != nil {
+		app.setSpells()
+	}
+}
+
+// setSourceChip renders the currently visible source(s) into the status
+// bar's source-filter chip.
+func (app *App) setSourceChip() {
+	if app.sourceFilter < 0 || app.sourceFilter >= len(app.sources) {
+		app.sourceChip.SetText("[All sources]")
+		return
+	}
+	app.sourceChip.SetText("[" + app.sources[app.sourceFilter].Name() + "]")
+}
+
+// openPalette shows the command palette overlay and focuses it.
+func (app *App) openPalette() {
+	app.pages.ShowPage("palette")
+	app.app.SetFocus(app.palette)
+}
+
+// closePalette hides the command palette overlay and returns focus to the
+// search input.
+func (app *App) closePalette() {
+	app.pages.HidePage("palette")
+	app.app.SetFocus(app.input)
 }
 
 func (app *App) setStatus(text string) {
@@ -160,41 +335,81 @@ func (app *App) focusWideBox() {
 	app.widebox.grid.SetBorderAttributes(tcell.AttrBold)
 }
 
-// Filters and sets the spells from app.spells and updates it on the screen
-// Does NOT update app.spells
+// spellMatch pairs the index of a spell in app.spells with its fuzzy match
+// against the current input, so the list can be sorted by score before
+// being rendered.
+type spellMatch struct {
+	index int
+	match match
+}
+
+// Filters and sets the spells from app.spells and updates it on the screen.
+// Candidates are fuzzy-matched against the current input and sorted
+// descending by match quality; anything scoring below fuzzyThreshold is
+// dropped. Does NOT update app.spells
 func (app *App) setSpells() {
 	app.list.Clear()
-	for i, s := range *app.spells {
-		lname := strings.ToLower(s.Name)
-		linput := strings.ToLower(app.inputText)
 
-		if strings.Contains(lname, linput) {
-			nameString := strconv.Itoa(s.Level) + " " + s.Name
+	spells := *app.spells
+	matches := make([]spellMatch, 0, len(spells))
+	for i, s := range spells {
+		if app.sourceFilter >= 0 && app.sourceFilter < len(app.sources) &&
+			s.Source != app.sources[app.sourceFilter].Name() {
+			continue
+		}
+
+		m := fuzzyScore(s.Name, app.inputText)
+		if app.inputText != "" && m.score < fuzzyThreshold {
+			continue
+		}
+		matches = append(matches, spellMatch{index: i, match: m})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].match.score > matches[j].match.score
+	})
+
+	for _, sm := range matches {
+		s := spells[sm.index]
+		prefix := strconv.Itoa(s.Level) + " "
+		nameString := prefix + s.Name
+
+		prepared := app.spellbook != nil && app.spellbook.IsPrepared(s.Name)
+
+		if s.Ritual || s.Concentration || prepared {
+			_, _, w, _ := app.list.Box.GetInnerRect()
+			padLen := w - len(nameString)
+			padNum := 0
+			if s.Concentration {
+				padNum++
+			}
+			if s.Ritual {
+				padNum++
+			}
+			if prepared {
+				padNum++
+			}
 
-			if s.Ritual || s.Concentration {
-				_, _, w, _ := app.list.Box.GetInnerRect()
-				padLen := w - len(nameString)
-				padNum := 0
+			if padLen >= 3 {
+				nameString += strings.Repeat(" ", padLen-padNum)
 				if s.Concentration {
-					padNum++
+					nameString += "C"
 				}
 				if s.Ritual {
-					padNum++
+					nameString += "R"
 				}
-
-				if padLen >= 3 {
-					nameString += strings.Repeat(" ", padLen-padNum)
-					if s.Concentration {
-						nameString += "C"
-					}
-					if s.Ritual {
-						nameString += "R"
-					}
+				if prepared {
+					nameString += "P"
 				}
 			}
+		}
 
-			app.list.AddItem(highlight(nameString, app.inputText), strconv.Itoa(i), 0, nil)
+		indices := make([]int, len(sm.match.indices))
+		for i, idx := range sm.match.indices {
+			indices[i] = idx + len(prefix)
 		}
+
+		app.list.AddItem(highlight(nameString, indices), strconv.Itoa(sm.index), 0, nil)
 	}
 }
 
@@ -215,39 +430,135 @@ func (app App) currentSelectedSpell() *Spell {
 // Handler than should be ran on every text input change. Filters the spell list
 // on text update.
 func (app *App) setInputText(text string) {
+	if app.suppressChange {
+		return
+	}
+
 	// focus the list on key input if the main content box happens to be focused atm
 	app.focusList()
+	app.completer.Reset()
+	app.ghost = ""
 	app.inputText = text
 	app.setSpells()
 }
 
-// Highlight a substring in a string regardless of it's capitalisation.
-// May not work properly with unicode
-func highlight(str, substr string) string {
-	lname := strings.ToLower(str)
-	linput := strings.ToLower(substr)
-	parts := strings.Split(lname, linput)
+// handleInputFieldInput handles keys specific to the search field: Ctrl-P/
+// Ctrl-N walk the query history, Tab cycles completions, Esc cancels a
+// pending completion, and Enter records the submitted query. Everything
+// else is returned unhandled so the global handler still sees it.
+func (app *App) handleInputFieldInput(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyCtrlP:
+		if entry, ok := app.history.Prev(); ok {
+			app.completer.Reset()
+			app.setGhostSuffix("")
+			app.input.SetText(entry)
+		}
+		return nil
+	case tcell.KeyCtrlN:
+		entry, _ := app.history.Next()
+		app.completer.Reset()
+		app.setGhostSuffix("")
+		app.input.SetText(entry)
+		return nil
+	case tcell.KeyTab:
+		app.setGhostSuffix(app.completer.Next(app.inputText))
+		return nil
+	case tcell.KeyEsc:
+		app.completer.Reset()
+		app.setGhostSuffix("")
+		return nil
+	case tcell.KeyEnter:
+		if app.ghost != "" {
+			full := app.inputText + app.ghost
+			app.completer.Reset()
+			app.ghost = ""
+			app.suppressChange = true
+			app.input.SetText(full)
+			app.suppressChange = false
+			app.inputText = full
+			app.setSpells()
+		}
+		app.history.Add(app.inputText)
+	}
+	return event
+}
+
+// setGhostSuffix previews suffix as dimmed text appended after the field's
+// real content, without committing it to app.inputText until accepted.
+func (app *App) setGhostSuffix(suffix string) {
+	app.ghost = suffix
+	app.suppressChange = true
+	if suffix == "" {
+		app.input.SetText(app.inputText)
+	} else {
+		app.input.SetText(app.inputText + "[gray]" + suffix + "[white]")
+	}
+	app.suppressChange = false
+}
+
+// completionCandidates returns spell names that literally start with
+// prefix (case-insensitively), ranked by the same fuzzy scorer used for
+// the list. Tab-completion renders its ghost suffix by slicing a
+// candidate at len(prefix), which only makes sense for a true prefix
+// match, so candidates here are restricted to that even though the list
+// itself allows scattered fuzzy matches.
+func (app *App) completionCandidates(prefix string) []string {
+	if app.spells == nil {
+		return nil
+	}
+
+	lowerPrefix := strings.ToLower(prefix)
+
+	type candidate struct {
+		name  string
+		score int
+	}
+	var candidates []candidate
+	for _, s := range *app.spells {
+		if !strings.HasPrefix(strings.ToLower(s.Name), lowerPrefix) {
+			continue
+		}
+		candidates = append(candidates, candidate{s.Name, fuzzyScore(s.Name, prefix).score})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	names := make([]string, len(candidates))
+	for i, c := range candidates {
+		names[i] = c.name
+	}
+	return names
+}
+
+// Highlight the runes of str at the given byte indices, used to mark the
+// individual characters a fuzzy match matched rather than a single
+// contiguous substring. May not work properly with unicode.
+func highlight(str string, indices []int) string {
+	if len(indices) == 0 {
+		return str
+	}
+
 	pre := "[#ff0000]"
 	post := "[white]"
 
-	// precalculated lengths for small performance benefits
-	prelen := len(pre)
-	postlen := len(post)
-	patternlen := len(substr)
+	marked := make(map[int]bool, len(indices))
+	for _, idx := range indices {
+		marked[idx] = true
+	}
 
-	var final string
-	for i, w := range parts {
-		startx := len(final)
-		if i > 1 {
-			startx -= (i - 1) * (prelen + postlen)
+	var final strings.Builder
+	for i, r := range str {
+		if marked[i] {
+			final.WriteString(pre)
+			final.WriteRune(r)
+			final.WriteString(post)
+			continue
 		}
-		if i != 0 {
-			final += pre + str[startx:startx+patternlen] + post
-			startx += patternlen
-		}
-		final += str[startx : startx+len(w)]
+		final.WriteRune(r)
 	}
-	return final
+	return final.String()
 }
 
 // Returns a pointer to a new list element preconfigured for the app
@@ -271,3 +582,11 @@ func getStatusBox() *tview.TextView {
 	box.SetBorder(false)
 	return box
 }
+
+// Returns a pointer to a new source-filter chip element preconfigured for
+// the app
+func getSourceChip() *tview.TextView {
+	chip := tview.NewTextView().SetTextAlign(tview.AlignCenter)
+	chip.SetBorder(false)
+	return chip
+}
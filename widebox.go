@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// WideBox is the detail pane on the right showing the currently selected
+// spell, laid out in a grid: the description on the left, and a narrow
+// slots column on the right showing the attached character's slot counts
+// and current concentration.
+type WideBox struct {
+	grid      *tview.Grid
+	detail    *tview.TextView
+	slots     *tview.TextView
+	spell     *Spell
+	book      *spellbook
+	rules     []syntaxRule
+	descCache map[string]string
+}
+
+// getWideBox returns a new WideBox preconfigured for the app.
+func getWideBox() *WideBox {
+	detail := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(true)
+
+	slots := tview.NewTextView().
+		SetDynamicColors(true)
+
+	grid := tview.NewGrid().
+		SetColumns(0, 20).
+		AddItem(detail, 0, 0, 1, 1, 0, 0, false).
+		AddItem(slots, 0, 1, 1, 1, 0, 0, false)
+	grid.SetBorder(true)
+
+	return &WideBox{
+		grid:      grid,
+		detail:    detail,
+		slots:     slots,
+		rules:     defaultSyntaxRules,
+		descCache: make(map[string]string),
+	}
+}
+
+// SetSpell renders spell's details into the box. The description is
+// tokenized against w.rules once per spell and the highlighted result is
+// cached, so switching back to a previously viewed spell doesn't re-run
+// every regex again.
+func (w *WideBox) SetSpell(spell *Spell) {
+	w.spell = spell
+
+	key := spell.Source + "\x00" + spell.Name
+	desc, ok := w.descCache[key]
+	if !ok {
+		desc = highlightDescription(spell.Description, w.rules)
+		w.descCache[key] = desc
+	}
+
+	w.detail.SetText(fmt.Sprintf("%s\n\n%s", spell.Name, desc))
+	w.detail.ScrollToBeginning()
+}
+
+// SetSpellbook attaches the active character's spellbook so the slots
+// column can be rendered and kept in sync as slots are expended or the
+// character changes what they're concentrating on.
+func (w *WideBox) SetSpellbook(book *spellbook) {
+	w.book = book
+	w.RefreshSlots()
+}
+
+// RefreshSlots re-renders the slots column from the attached spellbook.
+// Call it after any state that shows up there changes.
+func (w *WideBox) RefreshSlots() {
+	if w.book == nil {
+		return
+	}
+
+	var b strings.Builder
+	for level := 1; level <= 9; level++ {
+		s := w.book.SlotsAt(level)
+		if s.Max == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "Lvl %d: %d/%d\n", level, s.Current, s.Max)
+	}
+	if w.book.Concentrating != "" {
+		fmt.Fprintf(&b, "\nConcentrating:\n%s", w.book.Concentrating)
+	}
+	w.slots.SetText(b.String())
+}
+
+// ScrollUp scrolls the description up by one line.
+func (w *WideBox) ScrollUp() {
+	row, col := w.detail.GetScrollOffset()
+	if row > 0 {
+		row--
+	}
+	w.detail.ScrollTo(row, col)
+}
+
+// ScrollDown scrolls the description down by one line.
+func (w *WideBox) ScrollDown() {
+	row, col := w.detail.GetScrollOffset()
+	w.detail.ScrollTo(row+1, col)
+}